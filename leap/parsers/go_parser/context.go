@@ -0,0 +1,218 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// ContextGraph captures lightweight control- and data-flow context around a
+// log call, for downstream tools that want more structure than a raw code
+// snippet. Every field is derived textually from the AST (plus go/types
+// when available) rather than full dominance/data-flow analysis, so it's
+// best read as "the nearest enclosing/adjacent thing", not a proof.
+type ContextGraph struct {
+	Guards           []string         `json:"guards,omitempty"`
+	ErrorSource      string           `json:"error_source,omitempty"`
+	OnErrorBranch    bool             `json:"on_error_branch,omitempty"`
+	FreeVariables    []FreeVariable   `json:"free_variables,omitempty"`
+	SurroundingCalls SurroundingCalls `json:"surrounding_calls,omitempty"`
+}
+
+// FreeVariable is an identifier referenced in a log call's arguments, with
+// its declared type when go/types information is available for the file.
+type FreeVariable struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// SurroundingCalls names the nearest non-log calls before and after a log
+// call within the same function, for a sense of what the log is bracketing.
+type SurroundingCalls struct {
+	Previous string `json:"previous,omitempty"`
+	Next     string `json:"next,omitempty"`
+}
+
+// callRef is one call expression collected from a function body, used to
+// find the calls surrounding a given log call.
+type callRef struct {
+	name  string
+	pos   token.Pos
+	isLog bool
+}
+
+// buildContextGraph assembles the ContextGraph for a log call. argIndex is
+// the index of the template argument within call.Args, excluded from the
+// free-variable scan since it's already captured in LogTemplate.
+func (v *Visitor) buildContextGraph(call *ast.CallExpr, argIndex int) *ContextGraph {
+	return &ContextGraph{
+		Guards:           append([]string(nil), v.guards...),
+		ErrorSource:      v.lastErrAssign,
+		OnErrorBranch:    v.onErrorBranch(),
+		FreeVariables:    v.freeVariables(call, argIndex),
+		SurroundingCalls: v.surroundingCalls(call),
+	}
+}
+
+// onErrorBranch reports whether any enclosing guard looks like an error
+// check (e.g. "err != nil"). An else branch's guard is the negated if
+// condition, "!(...)", so there "err == nil" is the error check instead.
+func (v *Visitor) onErrorBranch() bool {
+	for _, guard := range v.guards {
+		check := "!= nil"
+		if strings.HasPrefix(guard, "!(") {
+			check = "== nil"
+		}
+		if strings.Contains(guard, "err") && strings.Contains(guard, check) {
+			return true
+		}
+	}
+	return false
+}
+
+// errAssignText reports the source text of the call assigned to an "err"
+// variable in assign, if any.
+func (v *Visitor) errAssignText(assign *ast.AssignStmt) (string, bool) {
+	foundErr := false
+	for _, lhs := range assign.Lhs {
+		if id, ok := lhs.(*ast.Ident); ok && id.Name == "err" {
+			foundErr = true
+		}
+	}
+	if !foundErr {
+		return "", false
+	}
+	for _, rhs := range assign.Rhs {
+		if call, ok := rhs.(*ast.CallExpr); ok {
+			return v.exprText(call), true
+		}
+	}
+	return "", false
+}
+
+// freeVariables collects the identifiers referenced in call's arguments
+// (other than the template argument at argIndex), resolving each one's
+// declared type via go/types when type information is available for the
+// file; it falls back to names only when typechecking fails or the
+// identifier can't be resolved.
+func (v *Visitor) freeVariables(call *ast.CallExpr, argIndex int) []FreeVariable {
+	seen := make(map[string]bool)
+	var vars []FreeVariable
+
+	var collect func(n ast.Node) bool
+	collect = func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			ast.Inspect(sel.X, collect)
+			return false
+		}
+		id, ok := n.(*ast.Ident)
+		if !ok || id.Name == "_" || seen[id.Name] {
+			return true
+		}
+
+		typeName := ""
+		if v.typesInfo != nil {
+			if obj := v.typesInfo.ObjectOf(id); obj != nil {
+				if _, isPkg := obj.(*types.PkgName); isPkg {
+					return true
+				}
+				if obj.Type() != nil {
+					typeName = obj.Type().String()
+				}
+			}
+		}
+
+		seen[id.Name] = true
+		vars = append(vars, FreeVariable{Name: id.Name, Type: typeName})
+		return true
+	}
+
+	for i, arg := range call.Args {
+		if i == argIndex {
+			continue
+		}
+		ast.Inspect(arg, collect)
+	}
+
+	return vars
+}
+
+// collectFuncCalls records every named call expression in body, in source
+// order, except those nested inside a log call's own chain or arguments,
+// so surroundingCalls can find the nearest non-log neighbors of a given
+// call.
+func (v *Visitor) collectFuncCalls(body *ast.BlockStmt) []callRef {
+	var calls []callRef
+	if body == nil {
+		return calls
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		name := calleeName(call)
+		if name == "" {
+			return true
+		}
+		funcName, _, _, _ := v.identifyLogCall(call)
+		calls = append(calls, callRef{name: name, pos: call.Pos(), isLog: funcName != ""})
+		return funcName == ""
+	})
+	return calls
+}
+
+// calleeName returns the displayed name of a call's target: the bare
+// function name, or the method name for a selector call.
+func calleeName(call *ast.CallExpr) string {
+	switch fn := call.Fun.(type) {
+	case *ast.Ident:
+		return fn.Name
+	case *ast.SelectorExpr:
+		return fn.Sel.Name
+	}
+	return ""
+}
+
+// surroundingCalls finds the nearest non-log calls before and after call
+// within the current function's call list.
+func (v *Visitor) surroundingCalls(call *ast.CallExpr) SurroundingCalls {
+	var sc SurroundingCalls
+	for i, c := range v.funcCalls {
+		if c.pos != call.Pos() {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if !v.funcCalls[j].isLog {
+				sc.Previous = v.funcCalls[j].name
+				break
+			}
+		}
+		for j := i + 1; j < len(v.funcCalls); j++ {
+			if !v.funcCalls[j].isLog {
+				sc.Next = v.funcCalls[j].name
+				break
+			}
+		}
+		break
+	}
+	return sc
+}
+
+// switchCaseGuard renders a switch/type-switch case clause as a normalized
+// boolean guard string, e.g. "status == 200 || status == 201".
+func switchCaseGuard(v *Visitor, tag string, exprs []ast.Expr) string {
+	if len(exprs) == 0 {
+		return "default"
+	}
+	parts := make([]string, 0, len(exprs))
+	for _, e := range exprs {
+		if tag != "" {
+			parts = append(parts, tag+" == "+v.exprText(e))
+		} else {
+			parts = append(parts, v.exprText(e))
+		}
+	}
+	return strings.Join(parts, " || ")
+}
@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestContextGraphGuardsAndErrorSource(t *testing.T) {
+	src := `package main
+func run(conn *Conn) {
+	err := conn.Open()
+	if err != nil {
+		log.Printf("open failed: %v", err)
+	}
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	cg := entries[0].ContextGraph
+	if cg == nil {
+		t.Fatal("context graph is nil")
+	}
+	if len(cg.Guards) != 1 || cg.Guards[0] != "err != nil" {
+		t.Errorf("guards = %v, want [\"err != nil\"]", cg.Guards)
+	}
+	if !cg.OnErrorBranch {
+		t.Error("OnErrorBranch = false, want true")
+	}
+	if cg.ErrorSource != "conn.Open()" {
+		t.Errorf("ErrorSource = %q, want \"conn.Open()\"", cg.ErrorSource)
+	}
+}
+
+func TestContextGraphFreeVariables(t *testing.T) {
+	src := `package main
+func run(userID int, name string) {
+	log.Printf("user %s (%d)", name, userID)
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	vars := entries[0].ContextGraph.FreeVariables
+	if len(vars) != 2 || vars[0].Name != "name" || vars[1].Name != "userID" {
+		t.Errorf("free variables = %+v, want [name userID]", vars)
+	}
+	// No go/types information is available for this fixture (no go.mod),
+	// so types are left unresolved rather than guessed.
+	for _, fv := range vars {
+		if fv.Type != "" {
+			t.Errorf("%s.Type = %q, want empty without type info", fv.Name, fv.Type)
+		}
+	}
+}
+
+func TestContextGraphSurroundingCalls(t *testing.T) {
+	src := `package main
+func run(id int) {
+	prepare(id)
+	log.Printf("processing %d", id)
+	finish(id)
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	sc := entries[0].ContextGraph.SurroundingCalls
+	if sc.Previous != "prepare" || sc.Next != "finish" {
+		t.Errorf("surrounding calls = %+v, want {prepare finish}", sc)
+	}
+}
+
+func TestContextGraphSwitchCaseGuard(t *testing.T) {
+	src := `package main
+func run(status int) {
+	switch status {
+	case 200, 201:
+		log.Printf("ok: %d", status)
+	}
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	guards := entries[0].ContextGraph.Guards
+	want := "status == 200 || status == 201"
+	if len(guards) != 1 || guards[0] != want {
+		t.Errorf("guards = %v, want [%q]", guards, want)
+	}
+}
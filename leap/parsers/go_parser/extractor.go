@@ -0,0 +1,542 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LogEntry represents a single extracted log statement
+type LogEntry struct {
+	ID                   string        `json:"id"`
+	Language             string        `json:"language"`
+	FilePath             string        `json:"file_path"`
+	LineNumber           int           `json:"line_number"`
+	FunctionName         string        `json:"function_name,omitempty"`
+	Package              string        `json:"package,omitempty"`
+	Module               string        `json:"module,omitempty"`
+	LogLevel             *string       `json:"log_level"`
+	Verbosity            *int          `json:"verbosity,omitempty"`
+	LogTemplate          string        `json:"log_template"`
+	TemplatePlaceholders []string      `json:"template_placeholders,omitempty"`
+	CodeContext          string        `json:"code_context"`
+	ContextGraph         *ContextGraph `json:"context_graph,omitempty"`
+	Fields               []LogField    `json:"fields,omitempty"`
+}
+
+// LogField is a single structured key/value attribute attached to a log
+// call, e.g. zerolog's .Str("user", u), logrus's WithFields(logrus.Fields{...}),
+// or slog's "user", u variadic pair.
+type LogField struct {
+	Name         string `json:"name"`
+	ValueExpr    string `json:"value_expr"`
+	InferredType string `json:"inferred_type,omitempty"`
+	Source       string `json:"source"` // chain, variadic, with_fields, log_attrs, capnslog
+}
+
+// filePackageInfo carries the package and module identity resolved for a
+// source file via go/packages, so entries can be attributed without each
+// Visitor re-resolving it. Syntax and TypesInfo are populated only when
+// packages.Load could type-check the file (a go.mod with resolvable
+// dependencies); Visitor falls back to parsing the file standalone, with no
+// type information, when they're nil.
+type filePackageInfo struct {
+	Package   string
+	Module    string
+	Fset      *token.FileSet
+	Syntax    *ast.File
+	TypesInfo *types.Info
+}
+
+// Visitor implements ast.Visitor for finding log calls
+type Visitor struct {
+	fset        *token.FileSet
+	filePath    string
+	sourceLines []string
+	entries     []LogEntry
+	currentFunc *ast.FuncDecl
+	pkg         filePackageInfo
+	fileConsts  map[string]string
+	typesInfo   *types.Info
+
+	// guards holds the normalized boolean expressions of enclosing
+	// if/for/switch blocks, outermost first.
+	guards []string
+	// lastErrAssign holds the source text of the most recent call assigned
+	// to an "err" variable, textually preceding the current position.
+	lastErrAssign string
+	// funcCalls lists every named call in the current function, in source
+	// order, for locating the calls surrounding a given log call.
+	funcCalls []callRef
+	// vGuards is a stack of verbosity levels from enclosing
+	// `if glog.V(n).Enabled() { ... }`-style guards, innermost last, used to
+	// tag logs that aren't themselves behind a `.V(n)` receiver.
+	vGuards []int
+	// capnslogLoggers maps a package-level capnslog logger variable to the
+	// repo/package tags it was constructed with via NewPackageLogger.
+	capnslogLoggers map[string]capnslogLoggerInfo
+}
+
+// parseGoFile parses a single Go source file and extracts its log entries.
+// pkg carries the package/module identity resolved for filePath, if any; if
+// pkg includes a type-checked Syntax tree, it's reused instead of
+// re-parsing so entries can carry go/types-resolved free-variable types.
+func parseGoFile(filePath string, pkg filePackageInfo) ([]LogEntry, error) {
+	// Read source file
+	sourceBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	source := string(sourceBytes)
+	sourceLines := strings.Split(source, "\n")
+
+	fset := pkg.Fset
+	node := pkg.Syntax
+	if node == nil {
+		fset = token.NewFileSet()
+		node, err = parser.ParseFile(fset, filePath, source, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Go source: %w", err)
+		}
+	}
+
+	// Create visitor and walk AST
+	v := &Visitor{
+		fset:            fset,
+		filePath:        filePath,
+		sourceLines:     sourceLines,
+		entries:         []LogEntry{},
+		pkg:             pkg,
+		fileConsts:      collectFileConstants(node),
+		typesInfo:       pkg.TypesInfo,
+		capnslogLoggers: collectCapnslogLoggers(node),
+	}
+
+	ast.Walk(v, node)
+
+	return v.entries, nil
+}
+
+// Visit implements ast.Visitor
+func (v *Visitor) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		oldFunc, oldCalls := v.currentFunc, v.funcCalls
+		v.currentFunc = n
+		v.funcCalls = v.collectFuncCalls(n.Body)
+		ast.Walk(v, n.Body)
+		v.currentFunc, v.funcCalls = oldFunc, oldCalls
+		return nil
+
+	case *ast.CallExpr:
+		if entry := v.extractLogEntry(n); entry != nil {
+			v.entries = append(v.entries, *entry)
+		}
+		return v
+
+	case *ast.BlockStmt:
+		for _, stmt := range n.List {
+			ast.Walk(v, stmt)
+			if assign, ok := stmt.(*ast.AssignStmt); ok {
+				if text, ok := v.errAssignText(assign); ok {
+					v.lastErrAssign = text
+				}
+			}
+		}
+		return nil
+
+	case *ast.IfStmt:
+		prevErr := v.lastErrAssign
+		if n.Init != nil {
+			ast.Walk(v, n.Init)
+			if assign, ok := n.Init.(*ast.AssignStmt); ok {
+				if text, ok := v.errAssignText(assign); ok {
+					v.lastErrAssign = text
+				}
+			}
+		}
+		guard := v.exprText(n.Cond)
+		v.guards = append(v.guards, guard)
+		if verbosity, ok := enabledGuardVerbosity(n.Cond); ok {
+			v.vGuards = append(v.vGuards, verbosity)
+			ast.Walk(v, n.Body)
+			v.vGuards = v.vGuards[:len(v.vGuards)-1]
+		} else {
+			ast.Walk(v, n.Body)
+		}
+		v.guards = v.guards[:len(v.guards)-1]
+		if n.Else != nil {
+			v.guards = append(v.guards, "!("+guard+")")
+			ast.Walk(v, n.Else)
+			v.guards = v.guards[:len(v.guards)-1]
+		}
+		v.lastErrAssign = prevErr
+		return nil
+
+	case *ast.ForStmt:
+		if n.Cond == nil {
+			ast.Walk(v, n.Body)
+			return nil
+		}
+		v.guards = append(v.guards, v.exprText(n.Cond))
+		ast.Walk(v, n.Body)
+		v.guards = v.guards[:len(v.guards)-1]
+		return nil
+
+	case *ast.RangeStmt:
+		v.guards = append(v.guards, "range "+v.exprText(n.X))
+		ast.Walk(v, n.Body)
+		v.guards = v.guards[:len(v.guards)-1]
+		return nil
+
+	case *ast.SwitchStmt:
+		tag := ""
+		if n.Tag != nil {
+			tag = v.exprText(n.Tag)
+		}
+		for _, stmt := range n.Body.List {
+			clause, ok := stmt.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			v.guards = append(v.guards, switchCaseGuard(v, tag, clause.List))
+			for _, body := range clause.Body {
+				ast.Walk(v, body)
+			}
+			v.guards = v.guards[:len(v.guards)-1]
+		}
+		return nil
+
+	case *ast.TypeSwitchStmt:
+		for _, stmt := range n.Body.List {
+			clause, ok := stmt.(*ast.CaseClause)
+			if !ok {
+				continue
+			}
+			v.guards = append(v.guards, switchCaseGuard(v, "", clause.List))
+			for _, body := range clause.Body {
+				ast.Walk(v, body)
+			}
+			v.guards = v.guards[:len(v.guards)-1]
+		}
+		return nil
+	}
+
+	return v
+}
+
+// extractLogEntry extracts a log entry from a call expression
+func (v *Visitor) extractLogEntry(call *ast.CallExpr) *LogEntry {
+	// Get the function being called
+	funcName, logLevel, verbosity, profile := v.identifyLogCall(call)
+	if funcName == "" {
+		return nil
+	}
+	if verbosity == nil && len(v.vGuards) > 0 {
+		guardVerbosity := v.vGuards[len(v.vGuards)-1]
+		verbosity = &guardVerbosity
+	}
+
+	// Get line number
+	pos := v.fset.Position(call.Pos())
+	lineNumber := pos.Line
+
+	argIndex := 0
+	if profile != nil {
+		argIndex = profile.templateArgIndex(funcName)
+	}
+
+	// Extract log template
+	logTemplate, placeholders := v.extractLogTemplate(call, argIndex, funcName)
+	if logTemplate == "" {
+		return nil
+	}
+
+	// Extract code context
+	codeContext := v.extractContext(call)
+
+	var fields []LogField
+	if profile != nil && profile.ExtractFields != nil {
+		fields = profile.ExtractFields(v, call, funcName)
+	}
+
+	functionName := ""
+	if v.currentFunc != nil && v.currentFunc.Name != nil {
+		functionName = v.currentFunc.Name.Name
+	}
+
+	entry := &LogEntry{
+		Language:             "go",
+		FilePath:             v.filePath,
+		LineNumber:           lineNumber,
+		FunctionName:         functionName,
+		Package:              v.pkg.Package,
+		Module:               v.pkg.Module,
+		LogLevel:             logLevel,
+		Verbosity:            verbosity,
+		LogTemplate:          logTemplate,
+		TemplatePlaceholders: placeholders,
+		CodeContext:          codeContext,
+		ContextGraph:         v.buildContextGraph(call, argIndex),
+		Fields:               fields,
+	}
+	entry.ID = entryID(entry.FilePath, entry.LineNumber, entry.LogTemplate)
+
+	return entry
+}
+
+// entryID derives a stable identifier for a log entry so downstream
+// consumers can diff extraction runs across commits.
+func entryID(filePath string, line int, logTemplate string) string {
+	sum := sha256.Sum256([]byte(filePath + ":" + strconv.Itoa(line) + ":" + logTemplate))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// identifyLogCall checks if a function call is a logging call, consulting
+// the registered LoggerProfiles in order. The returned verbosity is non-nil
+// when the call's receiver is a glog/klog/logger "V(n)" guard, e.g.
+// glog.V(2).Infof(...).
+func (v *Visitor) identifyLogCall(call *ast.CallExpr) (string, *string, *int, *LoggerProfile) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", nil, nil, nil
+	}
+
+	methodName := sel.Sel.Name
+	pkgIdent, isPkgCall := identName(sel.X)
+
+	// capnslog's per-package loggers are plain variables with no
+	// distinguishing receiver type to restrict Packages to, so a variable
+	// known to come from NewPackageLogger is checked first. Otherwise its
+	// bare "Info"-style calls would go unrecognized, since the built-in
+	// profiles that share those names (slog, zerolog, logrus, zap) reject a
+	// receiver they can't match, and the logger's repo/package tags would
+	// be lost.
+	if isPkgCall {
+		if _, ok := v.capnslogLoggers[pkgIdent]; ok {
+			if level, ok := capnslogProfile.Levels[methodName]; ok {
+				return methodName, &level, nil, &capnslogProfile
+			}
+		}
+	}
+
+	var verbosity *int
+	if !isPkgCall {
+		if recv, n, ok := vReceiverVerbosity(sel.X); ok {
+			pkgIdent, isPkgCall = recv, true
+			verbosity = &n
+		}
+	}
+
+	for i := range profileRegistry {
+		p := &profileRegistry[i]
+
+		// A resolved verbosity means sel.X already passed the stricter
+		// "glog.V(n)"-shaped structural check in vReceiverVerbosity, which
+		// is a stronger signal than the receiver's bare name — so it
+		// bypasses the Packages restriction that would otherwise reject a
+		// "logger" variable (glogProfile intentionally doesn't list
+		// "logger" in Packages, since on its own that name is too generic
+		// to disambiguate from zap/zerolog/logrus).
+		if len(p.Packages) > 0 && verbosity == nil && (!isPkgCall || !p.Packages[pkgIdent]) {
+			continue
+		}
+
+		// Chain terminators (zerolog's .Msg/.Msgf/.Send) carry the level on
+		// an earlier call in the chain rather than on methodName itself.
+		if p.ChainTerminators[methodName] {
+			if level := v.levelFromChain(p, sel.X); level != nil {
+				return methodName, level, verbosity, p
+			}
+			continue
+		}
+
+		if p.ResolveLevel != nil {
+			if level, ok := p.ResolveLevel(v, call, methodName); ok {
+				return methodName, &level, verbosity, p
+			}
+		}
+
+		if level, ok := p.Levels[methodName]; ok {
+			needsReceiverMatch := len(p.ReceiverTypes) > 0 &&
+				(p.RequireReceiverMatch == nil || p.RequireReceiverMatch[methodName])
+			if needsReceiverMatch && !v.receiverMatches(p, sel.X, pkgIdent, isPkgCall) {
+				continue
+			}
+			return methodName, &level, verbosity, p
+		}
+	}
+
+	return "", nil, nil, nil
+}
+
+// receiverMatches reports whether a profile that matches on method name
+// alone (Packages is nil but ReceiverTypes is set) should accept this call.
+// When the file's go/types information resolves the receiver to a type,
+// that type must contain one of ReceiverTypes; otherwise (no type
+// information, or a package identifier such as slog in slog.Info, which
+// has no type of its own) the call is only accepted through the library's
+// own package-qualified function
+// (FallbackPackageNames), since guessing from the method name alone is what
+// let one library's bare Info/Debug/Warn/Error calls be silently misread as
+// another's.
+func (v *Visitor) receiverMatches(p *LoggerProfile, receiver ast.Expr, pkgIdent string, isPkgCall bool) bool {
+	if len(p.ReceiverTypes) == 0 {
+		return true
+	}
+	if v.typesInfo != nil {
+		if t := v.typesInfo.TypeOf(receiver); t != nil && t != types.Typ[types.Invalid] {
+			typeName := t.String()
+			for _, want := range p.ReceiverTypes {
+				if strings.Contains(typeName, want) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if isPkgCall && p.FallbackPackageNames[pkgIdent] {
+		return true
+	}
+	if call, ok := receiver.(*ast.CallExpr); ok {
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok && p.FallbackChainMethods[sel.Sel.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// identName reports whether expr is a bare identifier, returning its name.
+// This is used to test a selector's receiver against LoggerProfile.Packages.
+func identName(expr ast.Expr) (string, bool) {
+	if id, ok := expr.(*ast.Ident); ok {
+		return id.Name, true
+	}
+	return "", false
+}
+
+// levelFromChain walks back through a fluent chain (e.g.
+// logger.With().Str(...).Error()) looking for the call that set the level.
+func (v *Visitor) levelFromChain(p *LoggerProfile, expr ast.Expr) *string {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if level, ok := p.Levels[sel.Sel.Name]; ok {
+		return &level
+	}
+	return v.levelFromChain(p, sel.X)
+}
+
+// extractLogTemplate extracts and normalizes the log message template from
+// call arguments. String constants referenced by name are propagated to
+// their literal value, string-concatenation trees are folded into a single
+// template with {argN} placeholders for their non-literal operands, and
+// printf verbs are normalized to {} so templates cluster across
+// format-string variants. Falls back to the argument's raw source text when
+// none of that applies (e.g. the template is itself a function call).
+func (v *Visitor) extractLogTemplate(call *ast.CallExpr, argIndex int, funcName string) (string, []string) {
+	if len(call.Args) <= argIndex {
+		return "", nil
+	}
+
+	templateArg := call.Args[argIndex]
+
+	raw, placeholders, ok := v.resolveTemplateExpr(templateArg, call.Pos())
+	if !ok {
+		return v.exprText(templateArg), nil
+	}
+
+	template := strconv.Quote(raw)
+	if strings.HasSuffix(funcName, "f") {
+		template = normalizePrintfVerbs(template)
+	}
+
+	return template, placeholders
+}
+
+// exprText returns the verbatim source text of expr, or a placeholder if it
+// spans multiple lines or falls outside the captured source.
+func (v *Visitor) exprText(expr ast.Expr) string {
+	pos := v.fset.Position(expr.Pos())
+	end := v.fset.Position(expr.End())
+
+	if pos.Line == end.Line && pos.Line > 0 && pos.Line <= len(v.sourceLines) {
+		line := v.sourceLines[pos.Line-1]
+		if pos.Column > 0 && end.Column > pos.Column && end.Column <= len(line)+1 {
+			return line[pos.Column-1 : end.Column-1]
+		}
+	}
+
+	return fmt.Sprintf("<expression at line %d>", pos.Line)
+}
+
+// literalStringValue returns the unquoted value of expr if it's a string
+// literal, or "" otherwise (e.g. when a field name is a variable).
+func literalStringValue(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// extractContext extracts surrounding code context
+func (v *Visitor) extractContext(call *ast.CallExpr) string {
+	if v.currentFunc != nil {
+		// Extract the entire function as context
+		startPos := v.fset.Position(v.currentFunc.Pos())
+		endPos := v.fset.Position(v.currentFunc.End())
+
+		if startPos.Line > 0 && endPos.Line <= len(v.sourceLines) {
+			contextLines := v.sourceLines[startPos.Line-1 : endPos.Line]
+			return strings.Join(contextLines, "\n")
+		}
+	}
+
+	// Fallback: return a few lines around the call
+	pos := v.fset.Position(call.Pos())
+	startLine := max(0, pos.Line-6)
+	endLine := min(len(v.sourceLines), pos.Line+2)
+
+	if startLine < endLine {
+		contextLines := v.sourceLines[startLine:endLine]
+		return strings.Join(contextLines, "\n")
+	}
+
+	return ""
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,167 @@
+package main
+
+import "testing"
+
+func intPtr(n int) *int { return &n }
+
+func TestGlogVerbosity(t *testing.T) {
+	tests := []struct {
+		name          string
+		src           string
+		wantVerbosity *int
+	}{
+		{
+			name: "direct V(n) receiver",
+			src: `package main
+func run() {
+	glog.V(2).Infof("cache miss for %s", key)
+}`,
+			wantVerbosity: intPtr(2),
+		},
+		{
+			name: "klog V(n) receiver",
+			src: `package main
+func run() {
+	klog.V(4).Info("debug detail")
+}`,
+			wantVerbosity: intPtr(4),
+		},
+		{
+			name: "logs inside an Enabled() guard inherit its verbosity",
+			src: `package main
+func run() {
+	if glog.V(3).Enabled() {
+		glog.Infof("expensive detail: %v", computeDetail())
+	}
+}`,
+			wantVerbosity: intPtr(3),
+		},
+		{
+			name: "unguarded call has no verbosity",
+			src: `package main
+func run() {
+	glog.Infof("plain message")
+}`,
+			wantVerbosity: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseSource(t, tt.src)
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+			}
+			got := entries[0].Verbosity
+			switch {
+			case tt.wantVerbosity == nil && got != nil:
+				t.Errorf("verbosity = %d, want nil", *got)
+			case tt.wantVerbosity != nil && (got == nil || *got != *tt.wantVerbosity):
+				t.Errorf("verbosity = %v, want %d", got, *tt.wantVerbosity)
+			}
+		})
+	}
+}
+
+func TestGlogDepthFamilySkipsLeadingArg(t *testing.T) {
+	src := `package main
+func run() {
+	glog.InfoDepth(1, "called from caller's caller")
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	want := `"called from caller's caller"`
+	if entries[0].LogTemplate != want {
+		t.Errorf("template = %q, want %q", entries[0].LogTemplate, want)
+	}
+}
+
+func TestCapnslogPackageLoggerFields(t *testing.T) {
+	src := `package main
+var plog = capnslog.NewPackageLogger("github.com/example/repo", "wal")
+func run() {
+	plog.Info("wal opened")
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	entry := entries[0]
+	if entry.LogLevel == nil || *entry.LogLevel != "info" {
+		t.Errorf("level = %v, want info", entry.LogLevel)
+	}
+	got := map[string]string{}
+	for _, f := range entry.Fields {
+		got[f.Name] = f.ValueExpr
+	}
+	if got["repo"] != `"github.com/example/repo"` || got["package"] != `"wal"` {
+		t.Errorf("fields = %+v, want repo/package tags", got)
+	}
+}
+
+// TestCapnslogWinsOverZerologForBareNames guards the short-circuit in
+// identifyLogCall: a capnslog per-package logger uses the same bare
+// "Info"-style method names as zerolog, logrus, etc., and those profiles
+// reject a receiver they can't match, so without checking
+// collectCapnslogLoggers first the call would go unrecognized and its
+// repo/package tags would be lost.
+func TestCapnslogWinsOverZerologForBareNames(t *testing.T) {
+	src := `package main
+var plog = capnslog.NewPackageLogger("repo", "pkg")
+func run() {
+	plog.Error("failed")
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	names := fieldNames(entries[0].Fields)
+	if len(names) != 2 || names[0] != "repo" || names[1] != "package" {
+		t.Errorf("fields = %v, want [repo package] (capnslog attribution)", names)
+	}
+}
+
+// TestUnrelatedBareCallsAreNotLogStatements guards against the false
+// positives stdLogProfile, capnslogProfile, and glogProfile used to produce
+// when a profile had no Packages/ReceiverTypes to restrict a bare method
+// name: a *testing.T assertion, an http.Error call, and an unrelated
+// struct's own Info method must never be picked up as log statements.
+func TestUnrelatedBareCallsAreNotLogStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{
+			name: "testing.T.Fatal is not the log package",
+			src: `package main
+func TestSomething(t *testing.T) {
+	t.Fatal("fixture setup failed")
+}`,
+		},
+		{
+			name: "http.Error is not a capnslog call",
+			src: `package main
+func handle(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, "bad request", http.StatusBadRequest)
+}`,
+		},
+		{
+			name: "a custom type's own Info method is not glog",
+			src: `package main
+func run(r Report) {
+	r.Info("summary ready")
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseSource(t, tt.src)
+			if len(entries) != 0 {
+				t.Fatalf("got %d entries, want 0: %+v", len(entries), entries)
+			}
+		})
+	}
+}
@@ -1,312 +1,334 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"go/ast"
-	"go/parser"
 	"go/token"
+	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/packages"
 )
 
-// LogEntry represents a single extracted log statement
-type LogEntry struct {
-	Language    string  `json:"language"`
-	FilePath    string  `json:"file_path"`
-	LineNumber  int     `json:"line_number"`
-	LogLevel    *string `json:"log_level"`
-	LogTemplate string  `json:"log_template"`
-	CodeContext string  `json:"code_context"`
+// Document is LEAP's top-level output format: a versioned envelope around
+// the extracted entries so downstream consumers can detect schema drift.
+type Document struct {
+	SchemaVersion string     `json:"schema_version"`
+	Tool          string     `json:"tool"`
+	Entries       []LogEntry `json:"entries"`
 }
 
-// Visitor implements ast.Visitor for finding log calls
-type Visitor struct {
-	fset        *token.FileSet
-	filePath    string
-	sourceLines []string
-	entries     []LogEntry
-	currentFunc *ast.FuncDecl
-}
+const schemaVersion = "1.0"
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "Usage: %s <go-file>\n", os.Args[0])
-		os.Exit(1)
+	profilesPath := flag.String("profiles", "", "path to a JSON file with additional LoggerProfile definitions")
+	format := flag.String("format", "json", "output format: json or ndjson")
+	flag.Parse()
+
+	if *profilesPath != "" {
+		if err := LoadLoggerProfilesFile(*profilesPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading logger profiles: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	filePath := os.Args[1]
+	args := flag.Args()
+	if len(args) < 1 {
+		fmt.Fprintf(os.Stderr, "Usage: %s [-profiles file.json] [-format=json|ndjson] <go-file|directory|->\n", os.Args[0])
+		os.Exit(1)
+	}
 
-	// Parse the Go file
-	entries, err := parseGoFile(filePath)
+	files, err := resolveFiles(args[0])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error resolving input: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Output as JSON
-	output, err := json.MarshalIndent(entries, "", "  ")
+	entries, err := extractAll(files)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error extracting log entries: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println(string(output))
+	if err := writeEntries(os.Stdout, entries, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		os.Exit(1)
+	}
 }
 
-func parseGoFile(filePath string) ([]LogEntry, error) {
-	// Read source file
-	sourceBytes, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file: %w", err)
+// writeEntries renders entries in the requested format: the versioned JSON
+// document by default, or one JSON object per line for "ndjson" so entries
+// can stream into tools like jq or ClickHouse without buffering the batch.
+func writeEntries(w io.Writer, entries []LogEntry, format string) error {
+	if format == "ndjson" {
+		enc := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := enc.Encode(entry); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	source := string(sourceBytes)
-	sourceLines := strings.Split(source, "\n")
-
-	// Parse Go source
-	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, source, parser.ParseComments)
+	doc := Document{SchemaVersion: schemaVersion, Tool: "LEAP", Entries: entries}
+	output, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse Go source: %w", err)
+		return err
 	}
-
-	// Create visitor and walk AST
-	v := &Visitor{
-		fset:        fset,
-		filePath:    filePath,
-		sourceLines: sourceLines,
-		entries:     []LogEntry{},
-	}
-
-	ast.Walk(v, node)
-
-	return v.entries, nil
+	_, err = fmt.Fprintln(w, string(output))
+	return err
 }
 
-// Visit implements ast.Visitor
-func (v *Visitor) Visit(node ast.Node) ast.Visitor {
-	if node == nil {
-		return nil
+// resolveFiles turns the CLI's single positional argument into a list of Go
+// source files: "-" reads a newline-separated list from stdin, a directory
+// is walked for .go files (skipping anything .gitignore excludes), and
+// anything else is treated as a single file.
+func resolveFiles(input string) ([]string, error) {
+	if input == "-" {
+		return readFileList(os.Stdin)
 	}
 
-	// Track current function for context
-	if funcDecl, ok := node.(*ast.FuncDecl); ok {
-		oldFunc := v.currentFunc
-		v.currentFunc = funcDecl
-		ast.Walk(v, funcDecl.Body)
-		v.currentFunc = oldFunc
-		return nil
+	info, err := os.Stat(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat input: %w", err)
 	}
-
-	// Check for function calls
-	if callExpr, ok := node.(*ast.CallExpr); ok {
-		if entry := v.extractLogEntry(callExpr); entry != nil {
-			v.entries = append(v.entries, *entry)
-		}
+	if !info.IsDir() {
+		return []string{input}, nil
 	}
 
-	return v
+	return walkGoFiles(input)
 }
 
-// extractLogEntry extracts a log entry from a call expression
-func (v *Visitor) extractLogEntry(call *ast.CallExpr) *LogEntry {
-	// Get the function being called
-	funcName, logLevel := v.identifyLogCall(call.Fun)
-	if funcName == "" {
-		return nil
-	}
-
-	// Get line number
-	pos := v.fset.Position(call.Pos())
-	lineNumber := pos.Line
-
-	// Extract log template
-	logTemplate := v.extractLogTemplate(call)
-	if logTemplate == "" {
-		return nil
+func readFileList(r io.Reader) ([]string, error) {
+	var files []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			files = append(files, line)
+		}
 	}
-
-	// Extract code context
-	codeContext := v.extractContext(call)
-
-	return &LogEntry{
-		Language:    "go",
-		FilePath:    v.filePath,
-		LineNumber:  lineNumber,
-		LogLevel:    logLevel,
-		LogTemplate: logTemplate,
-		CodeContext: codeContext,
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read file list: %w", err)
 	}
+	return files, nil
 }
 
-// identifyLogCall checks if a function call is a logging call
-func (v *Visitor) identifyLogCall(fun ast.Expr) (string, *string) {
-	switch expr := fun.(type) {
-	case *ast.SelectorExpr:
-		// Handle calls like log.Print(), logger.Info()
-		methodName := expr.Sel.Name
-
-		// Standard log package
-		if isStdLogMethod(methodName) {
-			level := getStdLogLevel(methodName)
-			return methodName, &level
+// walkGoFiles collects every .go file under root, honoring a top-level
+// .gitignore if one is present.
+func walkGoFiles(root string) ([]string, error) {
+	ignore := loadGitignore(root)
+
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
 
-		// Check for zerolog/logrus style: logger.Info(), logger.Error()
-		if isStructuredLogMethod(methodName) {
-			level := getStructuredLogLevel(methodName)
-			return methodName, &level
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			rel = path
 		}
 
-	case *ast.CallExpr:
-		// Handle chained calls like logger.Error().Msg("...")
-		if sel, ok := expr.Fun.(*ast.SelectorExpr); ok {
-			methodName := sel.Sel.Name
-			if methodName == "Msg" || methodName == "Msgf" {
-				// This is the final .Msg() call in a chain
-				// Try to determine level from the chain
-				level := v.extractLevelFromChain(expr)
-				return methodName, level
+		if d.IsDir() {
+			if rel != "." && (d.Name() == ".git" || ignore.matches(rel, true)) {
+				return filepath.SkipDir
 			}
+			return nil
 		}
-	}
-
-	return "", nil
-}
 
-// isStdLogMethod checks if a method is from the standard log package
-func isStdLogMethod(name string) bool {
-	stdMethods := map[string]bool{
-		"Print": true, "Printf": true, "Println": true,
-		"Fatal": true, "Fatalf": true, "Fatalln": true,
-		"Panic": true, "Panicf": true, "Panicln": true,
+		if ignore.matches(rel, false) {
+			return nil
+		}
+		if strings.HasSuffix(path, ".go") {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk %s: %w", root, err)
 	}
-	return stdMethods[name]
+
+	return files, nil
 }
 
-// getStdLogLevel returns the log level for standard log methods
-func getStdLogLevel(name string) string {
-	if strings.HasPrefix(name, "Fatal") {
-		return "fatal"
-	}
-	if strings.HasPrefix(name, "Panic") {
-		return "fatal"
-	}
-	return "info"
+// gitignore is a deliberately small .gitignore implementation: plain names,
+// "*" globs, and directory-only ("/"-suffixed) patterns from a single
+// top-level file. It doesn't support negation or nested .gitignore files.
+type gitignore struct {
+	patterns []string
 }
 
-// isStructuredLogMethod checks if a method is from structured logging libs
-func isStructuredLogMethod(name string) bool {
-	methods := map[string]bool{
-		"Debug": true, "Info": true, "Warn": true, "Warning": true,
-		"Error": true, "Fatal": true, "Panic": true,
-		"Trace": true, // Some loggers have Trace level
+func loadGitignore(root string) *gitignore {
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		return &gitignore{}
 	}
-	return methods[name]
-}
 
-// getStructuredLogLevel returns the log level for structured logging methods
-func getStructuredLogLevel(name string) string {
-	switch strings.ToLower(name) {
-	case "debug":
-		return "debug"
-	case "info":
-		return "info"
-	case "warn", "warning":
-		return "warn"
-	case "error":
-		return "error"
-	case "fatal", "panic":
-		return "fatal"
-	case "trace":
-		return "debug"
-	default:
-		return "info"
+	g := &gitignore{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		g.patterns = append(g.patterns, line)
 	}
+	return g
 }
 
-// extractLevelFromChain extracts log level from a chained call
-func (v *Visitor) extractLevelFromChain(call *ast.CallExpr) *string {
-	// Walk back through the chain to find the level method
-	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
-		if chainCall, ok := sel.X.(*ast.CallExpr); ok {
-			if chainSel, ok := chainCall.Fun.(*ast.SelectorExpr); ok {
-				methodName := chainSel.Sel.Name
-				if isStructuredLogMethod(methodName) {
-					level := getStructuredLogLevel(methodName)
-					return &level
-				}
-			}
+func (g *gitignore) matches(rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	for _, pattern := range g.patterns {
+		p := strings.TrimPrefix(pattern, "/")
+		dirOnly := strings.HasSuffix(p, "/")
+		p = strings.TrimSuffix(p, "/")
+		if dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := filepath.Match(p, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, filepath.Base(rel)); ok {
+			return true
 		}
 	}
-	return nil
+	return false
 }
 
-// extractLogTemplate extracts the log message template from call arguments
-func (v *Visitor) extractLogTemplate(call *ast.CallExpr) string {
-	if len(call.Args) == 0 {
-		return ""
+// extractAll parses every file in files using a worker pool sized to
+// GOMAXPROCS, attributing each entry with the package/module info resolved
+// via go/packages, and returns the results sorted by file path and line so
+// output stays stable across runs.
+func extractAll(files []string) ([]LogEntry, error) {
+	pkgInfo := loadPackageInfo(files)
+
+	jobs := make(chan string)
+	type result struct {
+		entries []LogEntry
+		err     error
 	}
+	results := make(chan result)
 
-	// Get the first argument (usually the message)
-	firstArg := call.Args[0]
-
-	// Handle string literals
-	if lit, ok := firstArg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
-		return lit.Value
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(files) {
+		workers = len(files)
+	}
+	if workers < 1 {
+		workers = 1
 	}
 
-	// Handle more complex expressions by converting to string
-	pos := v.fset.Position(firstArg.Pos())
-	end := v.fset.Position(firstArg.End())
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				entries, err := parseGoFile(file, pkgInfo[absPath(file)])
+				results <- result{entries: entries, err: err}
+			}
+		}()
+	}
 
-	if pos.Line == end.Line && pos.Line > 0 && pos.Line <= len(v.sourceLines) {
-		line := v.sourceLines[pos.Line-1]
-		if pos.Column > 0 && end.Column > pos.Column && end.Column <= len(line)+1 {
-			return line[pos.Column-1 : end.Column-1]
+	go func() {
+		for _, file := range files {
+			jobs <- file
 		}
+		close(jobs)
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var all []LogEntry
+	var firstErr error
+	for r := range results {
+		if r.err != nil {
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			continue
+		}
+		all = append(all, r.entries...)
+	}
+	if firstErr != nil {
+		return nil, firstErr
 	}
 
-	return fmt.Sprintf("<expression at line %d>", pos.Line)
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].FilePath != all[j].FilePath {
+			return all[i].FilePath < all[j].FilePath
+		}
+		return all[i].LineNumber < all[j].LineNumber
+	})
+
+	return all, nil
 }
 
-// extractContext extracts surrounding code context
-func (v *Visitor) extractContext(call *ast.CallExpr) string {
-	if v.currentFunc != nil {
-		// Extract the entire function as context
-		startPos := v.fset.Position(v.currentFunc.Pos())
-		endPos := v.fset.Position(v.currentFunc.End())
+// loadPackageInfo resolves the package name, module path, and (when the
+// build is healthy) type information for each file via go/packages, grouped
+// by directory. All packages share one FileSet so a file's Syntax tree and
+// TypesInfo stay position-compatible. Files whose package can't be resolved
+// or type-checked (no go.mod, broken build, ...) are simply left without
+// that metadata rather than failing the whole run; parseGoFile falls back
+// to parsing them standalone.
+func loadPackageInfo(files []string) map[string]filePackageInfo {
+	info := make(map[string]filePackageInfo, len(files))
+	fset := token.NewFileSet()
 
-		if startPos.Line > 0 && endPos.Line <= len(v.sourceLines) {
-			contextLines := v.sourceLines[startPos.Line-1 : endPos.Line]
-			return strings.Join(contextLines, "\n")
-		}
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		dirs[filepath.Dir(f)] = true
 	}
 
-	// Fallback: return a few lines around the call
-	pos := v.fset.Position(call.Pos())
-	startLine := max(0, pos.Line-6)
-	endLine := min(len(v.sourceLines), pos.Line+2)
-
-	if startLine < endLine {
-		contextLines := v.sourceLines[startLine:endLine]
-		return strings.Join(contextLines, "\n")
+	for dir := range dirs {
+		cfg := &packages.Config{
+			Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+				packages.NeedModule | packages.NeedSyntax | packages.NeedTypes |
+				packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+			Dir:  dir,
+			Fset: fset,
+		}
+		pkgs, err := packages.Load(cfg, ".")
+		if err != nil {
+			continue
+		}
+		for _, pkg := range pkgs {
+			module := ""
+			if pkg.Module != nil {
+				module = pkg.Module.Path
+			}
+			for i, f := range pkg.CompiledGoFiles {
+				entry := filePackageInfo{Package: pkg.Name, Module: module}
+				if i < len(pkg.Syntax) && len(pkg.Errors) == 0 {
+					entry.Fset = fset
+					entry.Syntax = pkg.Syntax[i]
+					entry.TypesInfo = pkg.TypesInfo
+				}
+				info[absPath(f)] = entry
+			}
+		}
 	}
 
-	return ""
+	return info
 }
 
-func max(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
+// absPath returns the absolute form of path, or path unchanged if it can't
+// be resolved. Used to key package metadata regardless of whether callers
+// passed relative or absolute file paths.
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
 	}
-	return b
+	return abs
 }
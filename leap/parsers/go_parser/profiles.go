@@ -0,0 +1,755 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoggerProfile describes how to recognize and interpret calls from a
+// particular logging library, so identifyLogCall doesn't need bespoke code
+// for every API shape. Built-in profiles cover the standard log package,
+// log/slog, zerolog, logrus, zap (sugared and non-sugared), glog/klog, and
+// capnslog; callers can add their own via RegisterLoggerProfile or a JSON
+// config file loaded with LoadLoggerProfilesFile.
+type LoggerProfile struct {
+	// Name identifies the profile for diagnostics and config files.
+	Name string
+
+	// Packages restricts this profile to package-level calls through one
+	// of these import identifiers (e.g. "slog", "glog", "klog"). Leave nil
+	// for profiles that match on method name alone, which covers
+	// instance-based loggers (zerolog, logrus, zap) whose receiver is a
+	// user-chosen variable rather than an import name.
+	Packages map[string]bool
+
+	// ReceiverTypes disambiguates a profile that otherwise matches on
+	// method name alone (Packages is nil): Debug/Info/Warn/Error are
+	// spelled identically across slog, zerolog, logrus, and zap, so
+	// without this a profile's registration order alone would decide
+	// which one claims an instance-based call. When the file's go/types
+	// information is available, the receiver's resolved type must contain
+	// one of these substrings (e.g. "slog.Logger") for the match to be
+	// accepted. Leave nil for profiles with no same-named competitors
+	// (std log, glog/klog, capnslog).
+	ReceiverTypes []string
+
+	// FallbackPackageNames is the conservative substitute for
+	// ReceiverTypes used when go/types information isn't available (no
+	// go.mod, or a build that doesn't resolve): it accepts a match only
+	// when the call goes through the library's own package-qualified
+	// convenience function (e.g. slog.Info(...), logrus.Info(...)), since
+	// that's unambiguous by name alone. Libraries with no such
+	// package-level function (zerolog, zap) are left unset, so without
+	// type information an instance-based call through them is left
+	// unclassified rather than guessed.
+	FallbackPackageNames map[string]bool
+
+	// FallbackChainMethods is the other conservative substitute for
+	// ReceiverTypes, for libraries with no package-level function but a
+	// distinctive chain call immediately before the level method (e.g.
+	// logrus's WithField/WithFields, zap's With). When type information
+	// isn't available, a bare match is accepted if the receiver is itself
+	// a call to one of these method names.
+	FallbackChainMethods map[string]bool
+
+	// RequireReceiverMatch narrows ReceiverTypes gating to the Levels
+	// entries that are actually spelled the same in another profile (e.g.
+	// "Info", shared by slog/zerolog/logrus/zap). A suffixed name unique
+	// to this profile (zap's Infow, Debugf, ...) needs no disambiguation
+	// and can be left out even though ReceiverTypes is set. Nil means
+	// every Levels entry is gated, which is correct whenever the whole
+	// set is ambiguous (slog, zerolog, logrus).
+	RequireReceiverMatch map[string]bool
+
+	// Levels maps a method/function name to its canonical log level.
+	Levels map[string]string
+
+	// ChainTerminators names the methods that close a fluent chain (e.g.
+	// zerolog's Msg/Msgf/Send). When methodName matches, identifyLogCall
+	// walks back through the chain's receiver expressions looking for the
+	// call that set the level, instead of consulting Levels directly.
+	ChainTerminators map[string]bool
+
+	// TemplateArgIndex returns the index of the message/template argument
+	// for a given method name. Most profiles use 0 for every method, so
+	// this may be left nil. slog's Logger.Log(ctx, level, msg, ...) and
+	// LogAttrs need index 2; the *Context variants need index 1.
+	TemplateArgIndex func(methodName string) int
+
+	// ResolveLevel handles calls whose level can't be read off the method
+	// name alone, such as slog.Log(ctx, slog.LevelWarn, ...). Return
+	// ok=false to fall back to Levels.
+	ResolveLevel func(v *Visitor, call *ast.CallExpr, methodName string) (level string, ok bool)
+
+	// ExtractFields pulls structured key/value attributes out of a matched
+	// call, e.g. zerolog's chained .Str()/.Int() calls or slog's variadic
+	// key/value pairs. May be nil for profiles with no structured fields.
+	ExtractFields func(v *Visitor, call *ast.CallExpr, methodName string) []LogField
+}
+
+func (p *LoggerProfile) templateArgIndex(methodName string) int {
+	if p.TemplateArgIndex == nil {
+		return 0
+	}
+	return p.TemplateArgIndex(methodName)
+}
+
+// profileRegistry holds every LoggerProfile consulted by identifyLogCall,
+// in registration order. Built-ins are added by init(); RegisterLoggerProfile
+// and LoadLoggerProfilesFile append to the same slice.
+var profileRegistry []LoggerProfile
+
+// RegisterLoggerProfile adds a LoggerProfile to the registry consulted by
+// identifyLogCall. Use this to teach LEAP about an in-house or uncommon
+// logging API without editing the visitor itself.
+func RegisterLoggerProfile(p LoggerProfile) {
+	profileRegistry = append(profileRegistry, p)
+}
+
+func init() {
+	RegisterLoggerProfile(stdLogProfile)
+	RegisterLoggerProfile(slogProfile)
+	RegisterLoggerProfile(zerologProfile)
+	RegisterLoggerProfile(logrusProfile)
+	RegisterLoggerProfile(zapProfile)
+	RegisterLoggerProfile(glogProfile)
+	// capnslogProfile is deliberately NOT registered here: its bare
+	// "Info"-style method names have no distinguishing receiver type to
+	// restrict Packages to, so matching it through the main registry loop
+	// would let it claim any unrelated call by name alone. identifyLogCall consults it directly,
+	// gated on v.capnslogLoggers, before the loop even starts.
+}
+
+var stdLogProfile = LoggerProfile{
+	Name: "log",
+	// Print/Fatal/Panic are generic enough to appear as methods on an
+	// unrelated type (t.Fatal in a test, a custom logger's own Print), so
+	// this profile is restricted to the actual "log" package identifier
+	// rather than matching on method name alone.
+	Packages: map[string]bool{"log": true},
+	Levels: map[string]string{
+		"Print": "info", "Printf": "info", "Println": "info",
+		"Fatal": "fatal", "Fatalf": "fatal", "Fatalln": "fatal",
+		"Panic": "fatal", "Panicf": "fatal", "Panicln": "fatal",
+	},
+}
+
+var slogProfile = LoggerProfile{
+	Name:                 "slog",
+	ReceiverTypes:        []string{"slog.Logger"},
+	FallbackPackageNames: map[string]bool{"slog": true},
+	Levels: map[string]string{
+		"Debug": "debug", "DebugContext": "debug",
+		"Info": "info", "InfoContext": "info",
+		"Warn": "warn", "WarnContext": "warn",
+		"Error": "error", "ErrorContext": "error",
+	},
+	TemplateArgIndex: slogTemplateArgIndex,
+	ResolveLevel:     resolveSlogLevel,
+	ExtractFields:    extractSlogFields,
+}
+
+func slogTemplateArgIndex(methodName string) int {
+	switch methodName {
+	case "DebugContext", "InfoContext", "WarnContext", "ErrorContext":
+		return 1
+	case "Log", "LogAttrs":
+		return 2
+	default:
+		return 0
+	}
+}
+
+// slogAttrFuncs names the slog.Attr constructor functions (slog.String,
+// slog.Int, ...) that may appear anywhere a key/value pair is expected.
+var slogAttrFuncs = map[string]bool{
+	"String": true, "Int": true, "Int64": true, "Uint64": true,
+	"Float64": true, "Bool": true, "Time": true, "Duration": true,
+	"Group": true, "Any": true,
+}
+
+// extractSlogFields collects slog's variadic key/value arguments (and
+// slog.Attr literals mixed in among them) into LogFields.
+func extractSlogFields(v *Visitor, call *ast.CallExpr, methodName string) []LogField {
+	argIdx := slogTemplateArgIndex(methodName)
+	if len(call.Args) <= argIdx+1 {
+		return nil
+	}
+	rest := call.Args[argIdx+1:]
+
+	if methodName == "LogAttrs" {
+		var fields []LogField
+		for _, arg := range rest {
+			if attr, ok := slogAttrField(v, arg); ok {
+				fields = append(fields, attr)
+			}
+		}
+		return fields
+	}
+
+	var fields []LogField
+	for i := 0; i < len(rest); {
+		if attr, ok := slogAttrField(v, rest[i]); ok {
+			fields = append(fields, attr)
+			i++
+			continue
+		}
+		if i+1 >= len(rest) {
+			break
+		}
+		if _, isCall := rest[i].(*ast.CallExpr); isCall {
+			// Not a recognized slog.Attr and not a plausible key: a call
+			// expression (e.g. another library's own field constructor)
+			// is never a valid slog key, so stop rather than pair it with
+			// whatever follows it.
+			break
+		}
+		name := literalStringValue(rest[i])
+		if name == "" {
+			name = v.exprText(rest[i])
+		}
+		fields = append(fields, LogField{
+			Name:      name,
+			ValueExpr: v.exprText(rest[i+1]),
+			Source:    "variadic",
+		})
+		i += 2
+	}
+	return fields
+}
+
+// slogAttrField recognizes a single slog.String("k", v)-style Attr literal.
+func slogAttrField(v *Visitor, expr ast.Expr) (LogField, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return LogField{}, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !slogAttrFuncs[sel.Sel.Name] {
+		return LogField{}, false
+	}
+	name, value := "", ""
+	if len(call.Args) > 0 {
+		name = literalStringValue(call.Args[0])
+	}
+	if len(call.Args) > 1 {
+		value = v.exprText(call.Args[1])
+	}
+	return LogField{
+		Name:         name,
+		ValueExpr:    value,
+		InferredType: strings.ToLower(sel.Sel.Name),
+		Source:       "log_attrs",
+	}, true
+}
+
+// resolveSlogLevel handles slog's Logger.Log(ctx, level, msg, ...) and
+// LogAttrs(ctx, level, msg, attrs...), whose level is an argument rather
+// than part of the method name.
+func resolveSlogLevel(v *Visitor, call *ast.CallExpr, methodName string) (string, bool) {
+	if methodName != "Log" && methodName != "LogAttrs" {
+		return "", false
+	}
+	if len(call.Args) < 2 {
+		return "", false
+	}
+	return slogLevelConstToString(call.Args[1])
+}
+
+// slogLevelConstToString maps a slog.LevelX selector expression to LEAP's
+// canonical level string. Custom levels built from arithmetic on the base
+// constants (e.g. slog.LevelInfo+2) aren't resolved and report ok=false.
+func slogLevelConstToString(arg ast.Expr) (string, bool) {
+	sel, ok := arg.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	switch sel.Sel.Name {
+	case "LevelDebug":
+		return "debug", true
+	case "LevelInfo":
+		return "info", true
+	case "LevelWarn":
+		return "warn", true
+	case "LevelError":
+		return "error", true
+	}
+	return "", false
+}
+
+var zerologProfile = LoggerProfile{
+	Name: "zerolog",
+	// zerolog's Level methods (Info(), Error(), ...) never take arguments
+	// directly; real usage always closes with a ChainTerminator, which
+	// doesn't need this. ReceiverTypes only guards the name-alone bare
+	// match below from claiming another library's identically-named call.
+	ReceiverTypes: []string{"zerolog.Logger", "zerolog.Context"},
+	Levels: map[string]string{
+		"Debug": "debug", "Info": "info", "Warn": "warn",
+		"Error": "error", "Fatal": "fatal", "Panic": "fatal",
+		"Trace": "debug",
+	},
+	ChainTerminators: map[string]bool{
+		"Msg": true, "Msgf": true, "Send": true,
+	},
+	ExtractFields: extractZerologFields,
+}
+
+// zerologFieldTypes maps zerolog's chained field-setter methods to the
+// LogField.InferredType they carry.
+var zerologFieldTypes = map[string]string{
+	"Str": "string", "Strs": "[]string",
+	"Int": "int", "Int64": "int64", "Int32": "int32",
+	"Uint": "uint", "Uint64": "uint64",
+	"Float64": "float64", "Float32": "float32",
+	"Bool": "bool", "Dur": "duration", "Time": "time",
+	"Err": "error", "Interface": "interface{}",
+}
+
+// extractZerologFields walks back through the chain of .Str()/.Int()/...
+// calls that precede the terminating .Msg()/.Msgf()/.Send().
+func extractZerologFields(v *Visitor, call *ast.CallExpr, methodName string) []LogField {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	var fields []LogField
+	expr := sel.X
+	for {
+		inner, ok := expr.(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		if typ, ok := zerologFieldTypes[innerSel.Sel.Name]; ok {
+			fields = append(fields, zerologField(v, innerSel.Sel.Name, typ, inner))
+		}
+		expr = innerSel.X
+	}
+
+	// Collected innermost-first (closest to the terminator); restore
+	// source order.
+	for i, j := 0, len(fields)-1; i < j; i, j = i+1, j-1 {
+		fields[i], fields[j] = fields[j], fields[i]
+	}
+	return fields
+}
+
+func zerologField(v *Visitor, methodName, typ string, call *ast.CallExpr) LogField {
+	if methodName == "Err" {
+		value := ""
+		if len(call.Args) > 0 {
+			value = v.exprText(call.Args[0])
+		}
+		return LogField{Name: "error", ValueExpr: value, InferredType: typ, Source: "chain"}
+	}
+
+	name, value := "", ""
+	if len(call.Args) > 0 {
+		name = literalStringValue(call.Args[0])
+	}
+	if len(call.Args) > 1 {
+		value = v.exprText(call.Args[1])
+	}
+	return LogField{Name: name, ValueExpr: value, InferredType: typ, Source: "chain"}
+}
+
+var logrusProfile = LoggerProfile{
+	Name:                 "logrus",
+	ReceiverTypes:        []string{"logrus.Logger", "logrus.Entry"},
+	FallbackPackageNames: map[string]bool{"logrus": true},
+	FallbackChainMethods: map[string]bool{"WithField": true, "WithFields": true},
+	Levels: map[string]string{
+		"Debug": "debug", "Info": "info",
+		"Warn": "warn", "Warning": "warn",
+		"Error": "error", "Fatal": "fatal", "Panic": "fatal",
+		"Trace": "debug",
+	},
+	ExtractFields: extractLogrusFields,
+}
+
+// extractLogrusFields recognizes logger.WithField("k", v).Info(...) and
+// logger.WithFields(logrus.Fields{...}).Info(...) immediately preceding the
+// level call.
+func extractLogrusFields(v *Visitor, call *ast.CallExpr, methodName string) []LogField {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	inner, ok := sel.X.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	innerSel, ok := inner.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	switch innerSel.Sel.Name {
+	case "WithField":
+		if len(inner.Args) < 2 {
+			return nil
+		}
+		return []LogField{{
+			Name:      literalStringValue(inner.Args[0]),
+			ValueExpr: v.exprText(inner.Args[1]),
+			Source:    "chain",
+		}}
+	case "WithFields":
+		if len(inner.Args) < 1 {
+			return nil
+		}
+		return fieldsFromCompositeLit(v, inner.Args[0], "with_fields")
+	}
+	return nil
+}
+
+// fieldsFromCompositeLit destructures a composite literal's key/value pairs,
+// as used by logrus.Fields{...}.
+func fieldsFromCompositeLit(v *Visitor, expr ast.Expr, source string) []LogField {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var fields []LogField
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		fields = append(fields, LogField{
+			Name:      literalStringValue(kv.Key),
+			ValueExpr: v.exprText(kv.Value),
+			Source:    source,
+		})
+	}
+	return fields
+}
+
+var zapProfile = LoggerProfile{
+	Name:                 "zap",
+	ReceiverTypes:        []string{"zap.Logger", "zap.SugaredLogger"},
+	FallbackChainMethods: map[string]bool{"With": true},
+	// Only the bare (non-sugared) names collide with another profile's
+	// identical method name; the *f/*w suffixed sugared/printf forms are
+	// unique to zap and need no disambiguation.
+	RequireReceiverMatch: map[string]bool{
+		"Debug": true, "Info": true, "Warn": true, "Error": true,
+		"DPanic": true, "Panic": true, "Fatal": true,
+	},
+	Levels: map[string]string{
+		"Debug": "debug", "Debugf": "debug", "Debugw": "debug",
+		"Info": "info", "Infof": "info", "Infow": "info",
+		"Warn": "warn", "Warnf": "warn", "Warnw": "warn",
+		"Error": "error", "Errorf": "error", "Errorw": "error",
+		"DPanic": "fatal", "DPanicf": "fatal", "DPanicw": "fatal",
+		"Panic": "fatal", "Panicf": "fatal", "Panicw": "fatal",
+		"Fatal": "fatal", "Fatalf": "fatal", "Fatalw": "fatal",
+	},
+	ExtractFields: extractZapFields,
+}
+
+// extractZapFields collects fields attached via a preceding .With(...) call
+// in the chain, plus the terminating call's own arguments: zap.Field values
+// (zap.String(...), ...) for the non-sugared methods, or alternating
+// key/value pairs for the sugared *w methods. The *f methods are
+// printf-style and carry no structured fields.
+func extractZapFields(v *Visitor, call *ast.CallExpr, methodName string) []LogField {
+	var fields []LogField
+
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if inner, ok := sel.X.(*ast.CallExpr); ok {
+			if innerSel, ok := inner.Fun.(*ast.SelectorExpr); ok && innerSel.Sel.Name == "With" {
+				fields = append(fields, zapFieldArgs(v, inner.Args, "chain")...)
+			}
+		}
+	}
+
+	if len(call.Args) < 1 {
+		return fields
+	}
+	rest := call.Args[1:]
+
+	switch {
+	case strings.HasSuffix(methodName, "w"):
+		fields = append(fields, keyValueFields(v, rest, "variadic")...)
+	case strings.HasSuffix(methodName, "f"):
+		// printf-style: no structured fields to extract.
+	default:
+		fields = append(fields, zapFieldArgs(v, rest, "variadic")...)
+	}
+
+	return fields
+}
+
+// zapFieldArgs treats each arg as a zap.String(...)/zap.Int(...)-style Field
+// constructor call.
+func zapFieldArgs(v *Visitor, args []ast.Expr, source string) []LogField {
+	var fields []LogField
+	for _, arg := range args {
+		call, ok := arg.(*ast.CallExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		name, value := "", ""
+		if len(call.Args) > 0 {
+			name = literalStringValue(call.Args[0])
+		}
+		if len(call.Args) > 1 {
+			value = v.exprText(call.Args[1])
+		}
+		fields = append(fields, LogField{
+			Name:         name,
+			ValueExpr:    value,
+			InferredType: strings.ToLower(sel.Sel.Name),
+			Source:       source,
+		})
+	}
+	return fields
+}
+
+// keyValueFields pairs up alternating key/value arguments, as used by zap's
+// sugared *w methods (Infow("msg", "k", v, ...)).
+func keyValueFields(v *Visitor, args []ast.Expr, source string) []LogField {
+	var fields []LogField
+	for i := 0; i+1 < len(args); i += 2 {
+		name := literalStringValue(args[i])
+		if name == "" {
+			name = v.exprText(args[i])
+		}
+		fields = append(fields, LogField{
+			Name:      name,
+			ValueExpr: v.exprText(args[i+1]),
+			Source:    source,
+		})
+	}
+	return fields
+}
+
+var glogProfile = LoggerProfile{
+	Name: "glog",
+	// Deliberately just "glog"/"klog": unlike those two, "logger" is the
+	// single most common logger variable name across unrelated libraries
+	// (zap, zerolog, logrus all favor it), and this profile has no
+	// ReceiverTypes to fall back on, so accepting it here would silently
+	// misattribute any of their bare calls to glog.
+	Packages: map[string]bool{
+		"glog": true, "klog": true,
+	},
+	Levels: map[string]string{
+		"Info": "info", "Infof": "info", "Infoln": "info",
+		"Warning": "warn", "Warningf": "warn", "Warningln": "warn",
+		"Error": "error", "Errorf": "error", "Errorln": "error",
+		"Fatal": "fatal", "Fatalf": "fatal", "Fatalln": "fatal",
+		"InfoDepth": "info", "InfoDepthf": "info",
+		"WarningDepth": "warn", "WarningDepthf": "warn",
+		"ErrorDepth": "error", "ErrorDepthf": "error",
+		"FatalDepth": "fatal", "FatalDepthf": "fatal",
+	},
+	TemplateArgIndex: glogTemplateArgIndex,
+}
+
+// glogTemplateArgIndex skips the leading call-depth argument for the
+// InfoDepth/WarningDepth/ErrorDepth/FatalDepth family (and their f-suffixed
+// variants), whose message/template argument is the second, not the first.
+func glogTemplateArgIndex(methodName string) int {
+	switch {
+	case strings.HasPrefix(methodName, "InfoDepth"),
+		strings.HasPrefix(methodName, "WarningDepth"),
+		strings.HasPrefix(methodName, "ErrorDepth"),
+		strings.HasPrefix(methodName, "FatalDepth"):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// vReceiverVerbosity recognizes the glog/klog "V(n)" verbosity-guard
+// pattern (glog.V(2), klog.V(4), logger.V(1)) as the receiver of a chained
+// call, returning the guard's package/variable name and verbosity level.
+func vReceiverVerbosity(expr ast.Expr) (string, int, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return "", 0, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "V" {
+		return "", 0, false
+	}
+	recv, ok := identName(sel.X)
+	if !ok || !(recv == "glog" || recv == "klog" || recv == "logger") {
+		return "", 0, false
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT {
+		return "", 0, false
+	}
+	n, err := strconv.Atoi(lit.Value)
+	if err != nil {
+		return "", 0, false
+	}
+	return recv, n, true
+}
+
+// enabledGuardVerbosity recognizes an `if glog.V(n).Enabled()` condition,
+// returning the guarded verbosity level so logs inside the body can be
+// tagged even though they aren't themselves a direct `.V(n)` call.
+func enabledGuardVerbosity(cond ast.Expr) (int, bool) {
+	call, ok := cond.(*ast.CallExpr)
+	if !ok {
+		return 0, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Enabled" {
+		return 0, false
+	}
+	_, verbosity, ok := vReceiverVerbosity(sel.X)
+	return verbosity, ok
+}
+
+var capnslogProfile = LoggerProfile{
+	Name: "capnslog",
+	Levels: map[string]string{
+		"Debug": "debug", "Info": "info", "Notice": "info",
+		"Warning": "warn", "Error": "error", "Critical": "fatal",
+	},
+	ExtractFields: extractCapnslogFields,
+}
+
+// capnslogLoggerInfo is the repo/package identity a capnslog logger
+// variable was constructed with via capnslog.NewPackageLogger(repo, pkg).
+type capnslogLoggerInfo struct {
+	Repo    string
+	Package string
+}
+
+// collectCapnslogLoggers scans file's top-level var declarations for the
+// `var log = capnslog.NewPackageLogger("repo", "pkg")` idiom, so calls made
+// through that variable can be tagged with its repo/package identity.
+func collectCapnslogLoggers(file *ast.File) map[string]capnslogLoggerInfo {
+	loggers := make(map[string]capnslogLoggerInfo)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || len(valueSpec.Names) != len(valueSpec.Values) {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				call, ok := valueSpec.Values[i].(*ast.CallExpr)
+				if !ok {
+					continue
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || sel.Sel.Name != "NewPackageLogger" {
+					continue
+				}
+				if pkgIdent, ok := identName(sel.X); !ok || pkgIdent != "capnslog" {
+					continue
+				}
+				if len(call.Args) < 2 {
+					continue
+				}
+				loggers[name.Name] = capnslogLoggerInfo{
+					Repo:    literalStringValue(call.Args[0]),
+					Package: literalStringValue(call.Args[1]),
+				}
+			}
+		}
+	}
+
+	return loggers
+}
+
+// extractCapnslogFields attaches the repo/package tags of the
+// NewPackageLogger-constructed variable a capnslog call was made through.
+func extractCapnslogFields(v *Visitor, call *ast.CallExpr, methodName string) []LogField {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	recv, ok := identName(sel.X)
+	if !ok {
+		return nil
+	}
+	info, ok := v.capnslogLoggers[recv]
+	if !ok {
+		return nil
+	}
+
+	var fields []LogField
+	if info.Repo != "" {
+		fields = append(fields, LogField{Name: "repo", ValueExpr: strconv.Quote(info.Repo), Source: "capnslog"})
+	}
+	if info.Package != "" {
+		fields = append(fields, LogField{Name: "package", ValueExpr: strconv.Quote(info.Package), Source: "capnslog"})
+	}
+	return fields
+}
+
+// jsonLoggerProfile is the on-disk representation accepted by
+// LoadLoggerProfilesFile. It covers the static parts of a LoggerProfile
+// (name, packages, levels, chain terminators); profiles that need custom
+// level resolution (slog.Log), a template argument other than the first
+// (glog's *Depth family), receiver-type gating, or field extraction must be
+// registered from Go via RegisterLoggerProfile instead.
+type jsonLoggerProfile struct {
+	Name             string            `json:"name"`
+	Packages         []string          `json:"packages"`
+	Levels           map[string]string `json:"levels"`
+	ChainTerminators []string          `json:"chain_terminators"`
+}
+
+// LoadLoggerProfilesFile reads a JSON array of logger profile definitions
+// from path and registers each one. This lets users teach LEAP about an
+// in-house logging wrapper without writing any Go code.
+func LoadLoggerProfilesFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read logger profiles file: %w", err)
+	}
+
+	var defs []jsonLoggerProfile
+	if err := json.Unmarshal(data, &defs); err != nil {
+		return fmt.Errorf("failed to parse logger profiles file: %w", err)
+	}
+
+	for _, d := range defs {
+		profile := LoggerProfile{
+			Name:   d.Name,
+			Levels: d.Levels,
+		}
+		if len(d.Packages) > 0 {
+			profile.Packages = make(map[string]bool, len(d.Packages))
+			for _, pkg := range d.Packages {
+				profile.Packages[pkg] = true
+			}
+		}
+		if len(d.ChainTerminators) > 0 {
+			profile.ChainTerminators = make(map[string]bool, len(d.ChainTerminators))
+			for _, m := range d.ChainTerminators {
+				profile.ChainTerminators[m] = true
+			}
+		}
+		RegisterLoggerProfile(profile)
+	}
+
+	return nil
+}
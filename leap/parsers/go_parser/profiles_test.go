@@ -0,0 +1,186 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// parseSource writes src to a temporary file and extracts its log entries
+// with no package/type information, mirroring how parseGoFile runs when
+// go/packages can't resolve a build (no go.mod).
+func parseSource(t *testing.T, src string) []LogEntry {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "main.go")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("failed to write fixture: %v", err)
+	}
+	entries, err := parseGoFile(path, filePackageInfo{})
+	if err != nil {
+		t.Fatalf("parseGoFile failed: %v", err)
+	}
+	return entries
+}
+
+// fieldNames returns the Name of every field in fields, for terse
+// assertions on which keys were captured.
+func fieldNames(fields []LogField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+func TestLoggerProfileFieldsAndLevel(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		wantLevel  string
+		wantFields []string
+	}{
+		{
+			name: "slog package-level variadic pairs",
+			src: `package main
+import "log/slog"
+func run() {
+	slog.Info("user signed in", "user", "bob", "attempt", 1)
+}`,
+			wantLevel:  "info",
+			wantFields: []string{"user", "attempt"},
+		},
+		{
+			name: "slog Attr literal mixed with a pair",
+			src: `package main
+import "log/slog"
+func run() {
+	slog.Warn("rate limited", slog.String("route", "/api"), "retry_after", 5)
+}`,
+			wantLevel:  "warn",
+			wantFields: []string{"route", "retry_after"},
+		},
+		{
+			name: "zerolog chain",
+			src: `package main
+func run() {
+	logger.Error().Str("user", "bob").Int("attempt", 3).Msg("login failed")
+}`,
+			wantLevel:  "error",
+			wantFields: []string{"user", "attempt"},
+		},
+		{
+			name: "logrus WithField chain",
+			src: `package main
+func run() {
+	logger.WithField("user", "bob").Info("login")
+}`,
+			wantLevel:  "info",
+			wantFields: []string{"user"},
+		},
+		{
+			name: "logrus WithFields composite literal",
+			src: `package main
+func run() {
+	logger.WithFields(logrus.Fields{"user": "bob", "attempt": 3}).Warn("retrying")
+}`,
+			wantLevel:  "warn",
+			wantFields: []string{"user", "attempt"},
+		},
+		{
+			name: "zap sugared Infow key/value pairs",
+			src: `package main
+func run() {
+	logger.Infow("processed items", "count", 5, "stage", "final")
+}`,
+			wantLevel:  "info",
+			wantFields: []string{"count", "stage"},
+		},
+		{
+			name: "zap With chain plus Field constructor args",
+			src: `package main
+func run() {
+	logger.With(zap.String("service", "api")).Error("failed", zap.Int("code", 500))
+}`,
+			wantLevel:  "error",
+			wantFields: []string{"service", "code"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseSource(t, tt.src)
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+			}
+			entry := entries[0]
+			if entry.LogLevel == nil || *entry.LogLevel != tt.wantLevel {
+				t.Errorf("level = %v, want %q", entry.LogLevel, tt.wantLevel)
+			}
+			got := fieldNames(entry.Fields)
+			if len(got) != len(tt.wantFields) {
+				t.Fatalf("fields = %v, want %v", got, tt.wantFields)
+			}
+			for i, name := range tt.wantFields {
+				if got[i] != name {
+					t.Errorf("fields[%d] = %q, want %q (all: %v)", i, got[i], name, got)
+				}
+			}
+		})
+	}
+}
+
+// TestAmbiguousBareNamesRequireReceiverType reproduces the two cases where a
+// bare Debug/Info/Warn/Error call used to be misattributed to whichever
+// unrestricted profile (slog) was registered first, fabricating a field out
+// of the next library's own field-constructor call or out of unrelated
+// positional arguments.
+func TestAmbiguousBareNamesRequireReceiverType(t *testing.T) {
+	t.Run("non-sugared zap call isn't read as slog", func(t *testing.T) {
+		src := `package main
+func run() {
+	logger.Info("processed items", zap.Int32("count", 5), zap.String("stage", "final"))
+}`
+		entries := parseSource(t, src)
+		// Without go/types information to confirm the receiver is really a
+		// *zap.Logger (or any other profile's logger type), this can't be
+		// safely attributed to anything: it must be omitted entirely rather
+		// than guessed as slog's variadic pairing, or silently picked up by
+		// an unrelated profile like glog just because the receiver is named
+		// "logger".
+		if len(entries) != 0 {
+			t.Fatalf("got %d entries, want 0 (bare \"logger\" receiver is ambiguous without type info): %+v", len(entries), entries)
+		}
+	})
+
+	t.Run("logrus positional args aren't read as a key/value pair", func(t *testing.T) {
+		src := `package main
+func run() {
+	logger.Error("connection failed", conn, retries)
+}`
+		entries := parseSource(t, src)
+		if len(entries) != 0 {
+			t.Fatalf("got %d entries, want 0 (bare \"logger\" receiver is ambiguous without type info): %+v", len(entries), entries)
+		}
+	})
+}
+
+// TestExtractSlogFieldsBailsOnNonKeyExpression covers the pairing loop
+// directly: a call expression can never be a valid slog key, so the loop
+// must stop instead of stitching it together with whatever argument
+// follows.
+func TestExtractSlogFieldsBailsOnNonKeyExpression(t *testing.T) {
+	src := `package main
+import "log/slog"
+func run() {
+	slog.Info("msg", "good_key", "good_value", unknownFunc(1, 2), "trailing")
+}`
+	entries := parseSource(t, src)
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	got := fieldNames(entries[0].Fields)
+	want := []string{"good_key"}
+	if len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("fields = %v, want %v (stop at the call expression)", got, want)
+	}
+}
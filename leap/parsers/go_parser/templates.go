@@ -0,0 +1,187 @@
+package main
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// printfVerbPattern matches a single printf verb, including its flags and
+// width/precision, e.g. "%d", "%+v", "%-6.2f". "%%" is handled separately so
+// a literal percent sign in a message isn't mistaken for a verb.
+var printfVerbPattern = regexp.MustCompile(`%[-+ 0#]*\d*(\.\d+)?[vTtbcdoqxXUeEfFgGsp]`)
+
+// normalizePrintfVerbs replaces printf verbs with a canonical "{}"
+// placeholder so templates that only differ by verb choice (%d vs %v) or
+// width/precision cluster together.
+func normalizePrintfVerbs(s string) string {
+	const escapedPercent = "\x00"
+	s = strings.ReplaceAll(s, "%%", escapedPercent)
+	s = printfVerbPattern.ReplaceAllString(s, "{}")
+	return strings.ReplaceAll(s, escapedPercent, "%%")
+}
+
+// collectFileConstants gathers top-level const and single-assignment var
+// string declarations, keyed by name, so identifiers used as a log template
+// (e.g. `const msgFmt = "user %d"` declared at package scope) can be resolved
+// back to their literal value.
+func collectFileConstants(file *ast.File) map[string]string {
+	consts := make(map[string]string)
+
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || (genDecl.Tok != token.CONST && genDecl.Tok != token.VAR) {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok || len(valueSpec.Names) != len(valueSpec.Values) {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if s, ok := literalStringExprValue(valueSpec.Values[i]); ok {
+					consts[name.Name] = s
+				}
+			}
+		}
+	}
+
+	return consts
+}
+
+// literalStringExprValue returns the unquoted value of expr if it's a
+// string literal.
+func literalStringExprValue(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return s, true
+}
+
+// resolveLocalConst looks for the nearest assignment to name inside the
+// current function that appears textually before beforePos, returning its
+// string value; ok is false when that nearest assignment isn't a string
+// literal. This is a textual approximation of dominance rather than a
+// real data-flow analysis, but it's enough to catch the common
+// `msgFmt := "..."` pattern a few lines above a log call.
+func (v *Visitor) resolveLocalConst(name string, beforePos token.Pos) (string, bool) {
+	if v.currentFunc == nil || v.currentFunc.Body == nil {
+		return "", false
+	}
+
+	var value string
+	var pos token.Pos
+	found, isLiteral := false, false
+
+	ast.Inspect(v.currentFunc.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || assign.Pos() >= beforePos {
+			return true
+		}
+		for i, lhs := range assign.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok || id.Name != name || (found && assign.Pos() <= pos) {
+				continue
+			}
+			found, pos = true, assign.Pos()
+			value, isLiteral = "", false
+			if i < len(assign.Rhs) {
+				value, isLiteral = literalStringExprValue(assign.Rhs[i])
+			}
+		}
+		return true
+	})
+
+	return value, found && isLiteral
+}
+
+// resolveConstIdent resolves name to a string constant, preferring the
+// nearest local assignment over a package-level const/var declaration.
+func (v *Visitor) resolveConstIdent(name string, beforePos token.Pos) (string, bool) {
+	if s, ok := v.resolveLocalConst(name, beforePos); ok {
+		return s, true
+	}
+	if s, ok := v.fileConsts[name]; ok {
+		return s, true
+	}
+	return "", false
+}
+
+// literalOrConstText returns the string value of expr if it's a literal or
+// an identifier resolvable via resolveConstIdent.
+func (v *Visitor) literalOrConstText(expr ast.Expr, beforePos token.Pos) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		return literalStringExprValue(e)
+	case *ast.Ident:
+		return v.resolveConstIdent(e.Name, beforePos)
+	}
+	return "", false
+}
+
+// flattenConcat flattens a left-associative chain of string "+" expressions
+// into its individual operands, in source order.
+func flattenConcat(expr ast.Expr) []ast.Expr {
+	if bin, ok := expr.(*ast.BinaryExpr); ok && bin.Op == token.ADD {
+		return append(flattenConcat(bin.X), flattenConcat(bin.Y)...)
+	}
+	return []ast.Expr{expr}
+}
+
+// foldConcat folds a string-concatenation tree into a single template,
+// replacing each non-literal operand with a numbered {argN} placeholder and
+// recording the operand's source text in the returned placeholder list.
+func (v *Visitor) foldConcat(expr ast.Expr, beforePos token.Pos) (string, []string) {
+	var template strings.Builder
+	var placeholders []string
+
+	for _, leaf := range flattenConcat(expr) {
+		if s, ok := v.literalOrConstText(leaf, beforePos); ok {
+			template.WriteString(s)
+			continue
+		}
+		template.WriteString("{arg")
+		template.WriteString(strconv.Itoa(len(placeholders)))
+		template.WriteString("}")
+		placeholders = append(placeholders, v.exprText(leaf))
+	}
+
+	return template.String(), placeholders
+}
+
+// resolveTemplateExpr resolves expr to a plain (unquoted) template string
+// plus any {argN} placeholders substituted for non-literal operands. ok is
+// false when expr is neither a literal, a resolvable identifier, nor a
+// string-concatenation tree, in which case the caller falls back to the raw
+// source text.
+func (v *Visitor) resolveTemplateExpr(expr ast.Expr, beforePos token.Pos) (string, []string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if s, ok := literalStringExprValue(e); ok {
+			return s, nil, true
+		}
+		return "", nil, false
+
+	case *ast.Ident:
+		if s, ok := v.resolveConstIdent(e.Name, beforePos); ok {
+			return s, nil, true
+		}
+		return "", nil, false
+
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", nil, false
+		}
+		template, placeholders := v.foldConcat(e, beforePos)
+		return template, placeholders, true
+	}
+
+	return "", nil, false
+}
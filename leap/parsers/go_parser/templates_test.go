@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestLogTemplateConstantPropagationAndNormalization(t *testing.T) {
+	tests := []struct {
+		name             string
+		src              string
+		wantTemplate     string
+		wantPlaceholders []string
+	}{
+		{
+			name: "package-level const resolved by name",
+			src: `package main
+const userFetchMsg = "fetching user %d"
+func run(id int) {
+	log.Printf(userFetchMsg, id)
+}`,
+			wantTemplate: `"fetching user {}"`,
+		},
+		{
+			name: "nearest preceding local assignment wins",
+			src: `package main
+func run(id int) {
+	msgFmt := "first %d"
+	msgFmt = "second %d"
+	log.Printf(msgFmt, id)
+}`,
+			wantTemplate: `"second {}"`,
+		},
+		{
+			name: "string concatenation folds into argN placeholders",
+			src: `package main
+func run(user string, id int) {
+	log.Print("user " + user + " fetched record " + toStr(id))
+}`,
+			wantTemplate:     `"user {arg0} fetched record {arg1}"`,
+			wantPlaceholders: []string{"user", "toStr(id)"},
+		},
+		{
+			name: "printf verb variants normalize to the same template",
+			src: `package main
+func run(id int) {
+	log.Printf("id=%05d done", id)
+}`,
+			wantTemplate: `"id={} done"`,
+		},
+		{
+			name: "escaped percent survives verb normalization",
+			src: `package main
+func run(id int) {
+	log.Printf("100%% done, id=%d", id)
+}`,
+			wantTemplate: `"100%% done, id={}"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseSource(t, tt.src)
+			if len(entries) != 1 {
+				t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+			}
+			entry := entries[0]
+			if entry.LogTemplate != tt.wantTemplate {
+				t.Errorf("template = %q, want %q", entry.LogTemplate, tt.wantTemplate)
+			}
+			if len(entry.TemplatePlaceholders) != len(tt.wantPlaceholders) {
+				t.Fatalf("placeholders = %v, want %v", entry.TemplatePlaceholders, tt.wantPlaceholders)
+			}
+			for i, ph := range tt.wantPlaceholders {
+				if entry.TemplatePlaceholders[i] != ph {
+					t.Errorf("placeholders[%d] = %q, want %q", i, entry.TemplatePlaceholders[i], ph)
+				}
+			}
+		})
+	}
+}